@@ -0,0 +1,140 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aws/karpenter/pkg/bootstrap"
+	"github.com/aws/karpenter/pkg/utils/awssession"
+)
+
+// initOptions holds the flags specific to `karpenter init`.
+type initOptions struct {
+	clusterName string
+	dryRun      bool
+	output      string
+}
+
+// NewInitCommand builds the `init` subcommand, which provisions the AWS
+// resources Karpenter needs before it can reconcile a cluster: the node IAM
+// role and instance profile, the SQS interruption queue, and the
+// EventBridge rules that feed it. It replaces the getting-started
+// CloudFormation stack with an idempotent, in-binary equivalent.
+func NewInitCommand() *cobra.Command {
+	o := &initOptions{}
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Provision the AWS resources Karpenter needs to run against this cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInit(cmd.Context(), o)
+		},
+	}
+	cmd.Flags().StringVar(&o.clusterName, "cluster-name", "", "The name of the EKS cluster Karpenter will run against (required)")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Print the changes that would be made without making them")
+	cmd.Flags().StringVar(&o.output, "output", "", "When set to 'yaml', emit a ready-to-apply EC2NodeClass/NodePool referencing the node role, printed after the planned changes and also under --dry-run")
+	_ = cmd.MarkFlagRequired("cluster-name")
+	return cmd
+}
+
+func runInit(ctx context.Context, o *initOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// Reuse the same session construction path as the controller's cloud
+	// provider so credentials and region discovery behave identically
+	// whether Karpenter is reconciling or bootstrapping.
+	sess, err := awssession.New()
+	if err != nil {
+		return fmt.Errorf("creating AWS session, %w", err)
+	}
+	b := bootstrap.New(sess)
+	opts := bootstrap.Options{ClusterName: o.clusterName}
+
+	plan, err := b.Plan(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("planning changes, %w", err)
+	}
+
+	if len(plan.Actions) == 0 {
+		fmt.Println("up to date, nothing to do")
+	} else {
+		fmt.Println("the following changes will be made:")
+		for _, action := range plan.Actions {
+			fmt.Printf("  * %s\n", action)
+		}
+	}
+
+	// plan.Resources is fully known once Plan returns, so --output yaml is
+	// honored for --dry-run too rather than only after Apply.
+	if o.output == "yaml" {
+		fmt.Println(renderManifest(plan.Resources))
+	}
+
+	if o.dryRun {
+		return nil
+	}
+
+	if len(plan.Actions) > 0 {
+		if err := b.Apply(ctx, opts, plan); err != nil {
+			return fmt.Errorf("applying changes, %w", err)
+		}
+	}
+	return nil
+}
+
+// ec2NodeClassTemplate is a hand-maintained, minimal EC2NodeClass/NodePool
+// pair referencing the role `init` creates. It's intentionally plain
+// YAML (rather than a marshaled API type, which would require importing
+// the v1beta1 CRD types into this binary) so the emitted manifest stays
+// readable as a starting point operators edit further.
+const ec2NodeClassTemplate = `apiVersion: karpenter.k8s.aws/v1beta1
+kind: EC2NodeClass
+metadata:
+  name: default
+spec:
+  role: %s
+  subnetSelectorTerms:
+    - tags:
+        karpenter.sh/discovery: %s
+  securityGroupSelectorTerms:
+    - tags:
+        karpenter.sh/discovery: %s
+  amiFamily: AL2
+---
+apiVersion: karpenter.sh/v1beta1
+kind: NodePool
+metadata:
+  name: default
+spec:
+  template:
+    spec:
+      nodeClassRef:
+        name: default
+      requirements:
+        - key: kubernetes.io/arch
+          operator: In
+          values: ["amd64"]
+        - key: karpenter.sh/capacity-type
+          operator: In
+          values: ["on-demand"]
+`
+
+func renderManifest(resources bootstrap.Resources) string {
+	return fmt.Sprintf(ec2NodeClassTemplate, resources.RoleName, resources.RoleName, resources.RoleName)
+}
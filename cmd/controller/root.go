@@ -0,0 +1,42 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/aws/karpenter/pkg/utils/options"
+)
+
+// NewRootCommand builds the `karpenter` CLI. Running `karpenter` with no
+// subcommand is shorthand for `karpenter run`, preserving the behavior of
+// the flat binary this replaces.
+func NewRootCommand() *cobra.Command {
+	runCmd := NewRunCommand(options.New())
+
+	root := &cobra.Command{
+		Use:           "karpenter",
+		Short:         "Karpenter is a node provisioning controller for Kubernetes",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ArbitraryArgs,
+		RunE:          runCmd.RunE,
+	}
+	root.Flags().AddFlagSet(runCmd.Flags())
+
+	root.AddCommand(runCmd)
+	root.AddCommand(NewInitCommand())
+	return root
+}
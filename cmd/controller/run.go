@@ -0,0 +1,275 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/go-logr/zapr"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+	"knative.dev/pkg/configmap/informer"
+	knativeinjection "knative.dev/pkg/injection"
+	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/signals"
+	"knative.dev/pkg/system"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+
+	"github.com/aws/karpenter/pkg/apis"
+	"github.com/aws/karpenter/pkg/cloudprovider"
+	cloudprovidermetrics "github.com/aws/karpenter/pkg/cloudprovider/metrics"
+	cloudproviderregistry "github.com/aws/karpenter/pkg/cloudprovider/registry"
+	"github.com/aws/karpenter/pkg/config"
+	"github.com/aws/karpenter/pkg/controllers"
+	"github.com/aws/karpenter/pkg/controllers/counter"
+	"github.com/aws/karpenter/pkg/controllers/health"
+	metricsnode "github.com/aws/karpenter/pkg/controllers/metrics/node"
+	metricspod "github.com/aws/karpenter/pkg/controllers/metrics/pod"
+	metricsprovisioner "github.com/aws/karpenter/pkg/controllers/metrics/provisioner"
+	"github.com/aws/karpenter/pkg/controllers/node"
+	"github.com/aws/karpenter/pkg/controllers/provisioning"
+	controllerregistry "github.com/aws/karpenter/pkg/controllers/registry"
+	"github.com/aws/karpenter/pkg/controllers/state"
+	"github.com/aws/karpenter/pkg/controllers/termination"
+	"github.com/aws/karpenter/pkg/events"
+	"github.com/aws/karpenter/pkg/utils/awssession"
+	"github.com/aws/karpenter/pkg/utils/injection"
+	"github.com/aws/karpenter/pkg/utils/options"
+	"github.com/aws/karpenter/pkg/utils/project"
+)
+
+const (
+	appName   = "karpenter"
+	component = "controller"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apis.AddToScheme(scheme))
+}
+
+// NewRunCommand builds the `run` subcommand, which starts the controller
+// manager and reconciles the cluster. This is the default behavior of the
+// `karpenter` binary.
+func NewRunCommand(opts *options.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Start the Karpenter controller manager",
+	}
+	cmd.Flags().AddGoFlagSet(opts.FlagSet)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return run(opts)
+	}
+	return cmd
+}
+
+func run(opts *options.Options) error {
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("validating options, %w", err)
+	}
+
+	controllerRuntimeConfig := controllerruntime.GetConfigOrDie()
+	controllerRuntimeConfig.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(float32(opts.KubeClientQPS), opts.KubeClientBurst)
+	controllerRuntimeConfig.UserAgent = appName
+	clientSet := kubernetes.NewForConfigOrDie(controllerRuntimeConfig)
+
+	cmw := informer.NewInformedWatcher(clientSet, system.Namespace())
+	// Set up logger and watch for changes to log level
+	ctx := LoggingContextOrDie(controllerRuntimeConfig, cmw)
+	ctx = injection.WithConfig(ctx, controllerRuntimeConfig)
+	ctx = injection.WithOptions(ctx, opts)
+
+	awsSession, err := awssession.New()
+	if err != nil {
+		return fmt.Errorf("creating AWS session, %w", err)
+	}
+	configMapCheck := health.NewConfigMapCheck()
+
+	logging.FromContext(ctx).Infof("Initializing with version %s", project.Version)
+	// Set up controller runtime controller
+	manager := controllers.NewManagerOrDie(ctx, controllerRuntimeConfig, controllerruntime.Options{
+		Logger:                     zapr.NewLogger(logging.FromContext(ctx).Desugar()),
+		LeaderElection:             opts.LeaderElect,
+		LeaderElectionID:           opts.LeaderElectResourceName,
+		LeaderElectionNamespace:    opts.LeaderElectResourceNamespace,
+		LeaderElectionResourceLock: opts.LeaderElectResourceLock,
+		LeaseDuration:              &opts.LeaderElectLeaseDuration,
+		RenewDeadline:              &opts.LeaderElectRenewDeadline,
+		RetryPeriod:                &opts.LeaderElectRetryPeriod,
+		Scheme:                     scheme,
+		MetricsBindAddress:         fmt.Sprintf(":%d", opts.MetricsPort),
+		HealthProbeBindAddress:     fmt.Sprintf(":%d", opts.HealthProbePort),
+	})
+
+	if opts.EnableProfiling {
+		utilruntime.Must(registerPprof(manager))
+	}
+
+	cloudProvider := cloudproviderregistry.NewCloudProvider(ctx, cloudprovider.Options{ClientSet: clientSet, KubeClient: manager.GetClient(), StartAsync: manager.Elected()})
+	cloudProvider = cloudprovidermetrics.Decorate(cloudProvider)
+
+	cfg, err := config.New(ctx, clientSet, cmw)
+	if err != nil {
+		// this does not happen if the config map is missing or invalid, only if some other error occurs
+		logging.FromContext(ctx).Fatalf("unable to load config, %s", err)
+	}
+
+	if err := cmw.Start(ctx.Done()); err != nil {
+		logging.FromContext(ctx).Errorf("watching configmaps, config changes won't be applied immediately, %s", err)
+	} else {
+		configMapCheck.MarkSynced()
+	}
+
+	recorder := events.NewDedupeRecorder(events.NewRecorder(manager.GetEventRecorderFor(appName)))
+	cluster := state.NewCluster(manager.GetClient(), cloudProvider)
+
+	// Domain-specific checks beyond the default ping: a stuck AWS control
+	// plane or a revoked IRSA token flips readiness off so kube-proxy stops
+	// routing leader-election traffic to a wedged pod, instead of silently
+	// hanging provisioning for minutes.
+	if err := registerHealthChecks(manager, awsSession, opts, configMapCheck, cluster); err != nil {
+		return fmt.Errorf("registering health checks, %w", err)
+	}
+
+	// registrations lists every controller this binary knows how to run. The
+	// DefaultEnabled bit controls whether "*" in --controllers starts it;
+	// operators running split HA topologies (e.g. a metrics-only replica
+	// separate from the provisioning leader) can shed the rest with
+	// --controllers=*,-metricspod,-counter and similar.
+	registrations := []controllerregistry.Registration{
+		{Name: "provisioning", Description: "Provisions nodes for unschedulable pods", DefaultEnabled: true,
+			Controller: provisioning.NewController(ctx, cfg, manager.GetClient(), clientSet.CoreV1(), recorder, cloudProvider, cluster)},
+		{Name: "state-node", Description: "Keeps the in-memory cluster state in sync with node changes", DefaultEnabled: true,
+			Controller: state.NewNodeController(manager.GetClient(), cluster)},
+		{Name: "state-pod", Description: "Keeps the in-memory cluster state in sync with pod changes", DefaultEnabled: true,
+			Controller: state.NewPodController(manager.GetClient(), cluster)},
+		{Name: "termination", Description: "Terminates nodes and backing cloud provider instances on deletion", DefaultEnabled: true,
+			Controller: termination.NewController(ctx, manager.GetClient(), clientSet.CoreV1(), cloudProvider)},
+		{Name: "node", Description: "Reconciles node readiness, labels and taints", DefaultEnabled: true,
+			Controller: node.NewController(manager.GetClient(), cloudProvider)},
+		{Name: "metricspod", Description: "Emits per-pod scheduling metrics", DefaultEnabled: true,
+			Controller: metricspod.NewController(manager.GetClient())},
+		{Name: "metricsnode", Description: "Emits per-node metrics", DefaultEnabled: true,
+			Controller: metricsnode.NewController(manager.GetClient())},
+		{Name: "metricsprovisioner", Description: "Emits per-provisioner metrics", DefaultEnabled: true,
+			Controller: metricsprovisioner.NewController(manager.GetClient())},
+		{Name: "counter", Description: "Maintains provisioner resource usage counters", DefaultEnabled: true,
+			Controller: counter.NewController(manager.GetClient(), cluster)},
+	}
+
+	enabled, err := controllerregistry.Resolve(opts.Controllers, registrations)
+	if err != nil {
+		return fmt.Errorf("resolving --controllers, %w", err)
+	}
+	logging.FromContext(ctx).Infof("starting controllers %v", names(enabled))
+	skippedNames := skipped(registrations, enabled)
+	logging.FromContext(ctx).Infof("skipping controllers %v", skippedNames)
+	controllers.RecordDisabledControllers(skippedNames...)
+
+	toStart := make([]controllers.Controller, 0, len(enabled))
+	for _, r := range enabled {
+		toStart = append(toStart, r.Controller)
+	}
+	if err := manager.RegisterControllers(ctx, toStart...).Start(ctx); err != nil {
+		return fmt.Errorf("unable to start manager, %w", err)
+	}
+	return nil
+}
+
+func names(registrations []controllerregistry.Registration) []string {
+	names := make([]string, 0, len(registrations))
+	for _, r := range registrations {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+func skipped(all, enabled []controllerregistry.Registration) []string {
+	enabledNames := map[string]bool{}
+	for _, r := range enabled {
+		enabledNames[r.Name] = true
+	}
+	skipped := []string{}
+	for _, r := range all {
+		if !enabledNames[r.Name] {
+			skipped = append(skipped, r.Name)
+		}
+	}
+	return skipped
+}
+
+func registerHealthChecks(manager controllers.Manager, awsSession *session.Session, opts *options.Options, configMapCheck *health.ConfigMapCheck, cluster *state.Cluster) error {
+	readyzChecks := []health.Checker{
+		health.NewEC2Check(ec2.New(awsSession)),
+		health.NewSQSCheck(sqs.New(awsSession), opts.AWSInterruptionQueueName),
+		health.NewCredentialsCheck(sts.New(awsSession)),
+		health.NewClusterSyncedCheck(cluster),
+	}
+	for _, checker := range readyzChecks {
+		if err := manager.AddReadyzCheck(checker.Name(), health.AsHealthzCheck(checker)); err != nil {
+			return err
+		}
+	}
+	return manager.AddHealthzCheck(configMapCheck.Name(), health.AsHealthzCheck(configMapCheck))
+}
+
+func registerPprof(manager controllers.Manager) error {
+	for path, handler := range map[string]http.Handler{
+		"/debug/pprof/":             http.HandlerFunc(pprof.Index),
+		"/debug/pprof/cmdline":      http.HandlerFunc(pprof.Cmdline),
+		"/debug/pprof/profile":      http.HandlerFunc(pprof.Profile),
+		"/debug/pprof/symbol":       http.HandlerFunc(pprof.Symbol),
+		"/debug/pprof/trace":        http.HandlerFunc(pprof.Trace),
+		"/debug/pprof/allocs":       pprof.Handler("allocs"),
+		"/debug/pprof/heap":         pprof.Handler("heap"),
+		"/debug/pprof/block":        pprof.Handler("block"),
+		"/debug/pprof/goroutine":    pprof.Handler("goroutine"),
+		"/debug/pprof/threadcreate": pprof.Handler("threadcreate"),
+	} {
+		err := manager.AddMetricsExtraHandler(path, handler)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoggingContextOrDie injects a logger into the returned context. The logger is
+// configured by the ConfigMap `config-logging` and live updates the level.
+func LoggingContextOrDie(config *rest.Config, cmw *informer.InformedWatcher) context.Context {
+	ctx, startinformers := knativeinjection.EnableInjectionOrDie(signals.NewContext(), config)
+	logger, atomicLevel := sharedmain.SetupLoggerOrDie(ctx, component)
+	ctx = logging.WithLogger(ctx, logger)
+	rest.SetDefaultWarningHandler(&logging.WarningHandler{Logger: logger})
+	sharedmain.WatchLoggingConfigOrDie(ctx, cmw, logger, atomicLevel, component)
+	startinformers()
+	return ctx
+}
@@ -0,0 +1,199 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bootstrap provisions the AWS resources Karpenter needs before it
+// can reconcile a cluster: the node IAM role and instance profile, the SQS
+// interruption queue, and the EventBridge rules that feed it. It backs the
+// `karpenter init` CLI subcommand and is the programmatic equivalent of the
+// getting-started CloudFormation stack.
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// managedPolicies are attached to the generated KarpenterNodeRole, matching
+// the getting-started CloudFormation template.
+var managedPolicies = []string{
+	"arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy",
+	"arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy",
+	"arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly",
+	"arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore",
+}
+
+const ec2TrustPolicy = `{
+	"Version": "2012-10-17",
+	"Statement": [
+		{
+			"Effect": "Allow",
+			"Principal": {"Service": "ec2.amazonaws.com"},
+			"Action": "sts:AssumeRole"
+		}
+	]
+}`
+
+// interruptionRules maps an EventBridge rule-name suffix to the event
+// pattern it matches, routing Spot interruption, rebalance, and
+// state-change notifications to the interruption queue.
+var interruptionRules = map[string]string{
+	"SpotInterruption":        `{"source":["aws.ec2"],"detail-type":["EC2 Spot Instance Interruption Warning"]}`,
+	"RebalanceRecommendation": `{"source":["aws.ec2"],"detail-type":["EC2 Instance Rebalance Recommendation"]}`,
+	"InstanceStateChange":     `{"source":["aws.ec2"],"detail-type":["EC2 Instance State-change Notification"]}`,
+}
+
+// Options configures a bootstrap run.
+type Options struct {
+	// ClusterName is used to derive the default resource names, e.g.
+	// KarpenterNodeRole-$ClusterName.
+	ClusterName string
+}
+
+// Resources names every AWS resource a bootstrap run manages.
+type Resources struct {
+	RoleName            string
+	InstanceProfileName string
+	QueueName           string
+}
+
+// NewResources derives the standard resource names for a cluster, matching
+// the names the getting-started CloudFormation template and the
+// "unmanaged instance profile" integration test expect.
+func NewResources(clusterName string) Resources {
+	return Resources{
+		RoleName:            fmt.Sprintf("KarpenterNodeRole-%s", clusterName),
+		InstanceProfileName: fmt.Sprintf("KarpenterNodeInstanceProfile-%s", clusterName),
+		QueueName:           fmt.Sprintf("Karpenter-%s", clusterName),
+	}
+}
+
+// Plan is the set of changes a bootstrap run would make, computed up front
+// so `karpenter init --dry-run` can print it without touching any account.
+type Plan struct {
+	Resources Resources
+	// Actions is an ordered, human readable diff, e.g. "create IAM role
+	// KarpenterNodeRole-my-cluster". Only existence is checked: a resource
+	// that already exists produces no action, even if its attached policies,
+	// role, targets or queue policy differ from what Apply would set.
+	Actions []string
+}
+
+// Bootstrapper provisions the AWS prerequisites for a cluster. It is built
+// on the same session construction path used by the controller's cloud
+// provider so credentials, region discovery, and retries behave identically
+// whether Karpenter is reconciling or bootstrapping.
+type Bootstrapper struct {
+	iam    iamAPI
+	sqs    sqsAPI
+	events eventsAPI
+}
+
+// New constructs a Bootstrapper from an AWS session.
+func New(sess *session.Session) *Bootstrapper {
+	return &Bootstrapper{
+		iam:    newIAMClient(sess),
+		sqs:    newSQSClient(sess),
+		events: newEventsClient(sess),
+	}
+}
+
+// Plan computes the changes needed to bring the account to the desired
+// state for opts.ClusterName, without mutating anything.
+func (b *Bootstrapper) Plan(ctx context.Context, opts Options) (*Plan, error) {
+	resources := NewResources(opts.ClusterName)
+	plan := &Plan{Resources: resources}
+
+	roleExists, err := b.iam.roleExists(ctx, resources.RoleName)
+	if err != nil {
+		return nil, fmt.Errorf("checking IAM role %s, %w", resources.RoleName, err)
+	}
+	if !roleExists {
+		plan.Actions = append(plan.Actions, fmt.Sprintf("create IAM role %s with policies %v", resources.RoleName, managedPolicies))
+	}
+
+	profileExists, err := b.iam.instanceProfileExists(ctx, resources.InstanceProfileName)
+	if err != nil {
+		return nil, fmt.Errorf("checking instance profile %s, %w", resources.InstanceProfileName, err)
+	}
+	if !profileExists {
+		plan.Actions = append(plan.Actions, fmt.Sprintf("create instance profile %s with role %s", resources.InstanceProfileName, resources.RoleName))
+	}
+
+	queueExists, err := b.sqs.queueExists(ctx, resources.QueueName)
+	if err != nil {
+		return nil, fmt.Errorf("checking SQS queue %s, %w", resources.QueueName, err)
+	}
+	if !queueExists {
+		plan.Actions = append(plan.Actions, fmt.Sprintf("create SQS interruption queue %s", resources.QueueName))
+	}
+
+	for _, suffix := range sortedKeys(interruptionRules) {
+		ruleName := fmt.Sprintf("Karpenter-%s-%s", opts.ClusterName, suffix)
+		ruleExists, err := b.events.ruleExists(ctx, ruleName)
+		if err != nil {
+			return nil, fmt.Errorf("checking EventBridge rule %s, %w", ruleName, err)
+		}
+		if !ruleExists {
+			plan.Actions = append(plan.Actions, fmt.Sprintf("create EventBridge rule %s routing to %s", ruleName, resources.QueueName))
+		}
+	}
+
+	return plan, nil
+}
+
+// Apply idempotently creates every resource described by plan. It is safe
+// to call repeatedly. Resources that already exist are not recreated, but
+// they are brought in line: the managed policies are attached to an existing
+// role, the role is added to an existing instance profile that has none, the
+// EventBridge rules and their targets are put again, and the queue policy is
+// overwritten.
+func (b *Bootstrapper) Apply(ctx context.Context, opts Options, plan *Plan) error {
+	if err := b.iam.ensureRole(ctx, plan.Resources.RoleName, ec2TrustPolicy, managedPolicies); err != nil {
+		return fmt.Errorf("ensuring IAM role %s, %w", plan.Resources.RoleName, err)
+	}
+	if err := b.iam.ensureInstanceProfile(ctx, plan.Resources.InstanceProfileName, plan.Resources.RoleName); err != nil {
+		return fmt.Errorf("ensuring instance profile %s, %w", plan.Resources.InstanceProfileName, err)
+	}
+	queueARN, err := b.sqs.ensureQueue(ctx, plan.Resources.QueueName)
+	if err != nil {
+		return fmt.Errorf("ensuring SQS queue %s, %w", plan.Resources.QueueName, err)
+	}
+	ruleARNs := make([]string, 0, len(interruptionRules))
+	for _, suffix := range sortedKeys(interruptionRules) {
+		ruleName := fmt.Sprintf("Karpenter-%s-%s", opts.ClusterName, suffix)
+		ruleARN, err := b.events.ensureRule(ctx, ruleName, interruptionRules[suffix], queueARN)
+		if err != nil {
+			return fmt.Errorf("ensuring EventBridge rule %s, %w", ruleName, err)
+		}
+		ruleARNs = append(ruleARNs, ruleARN)
+	}
+	if err := b.sqs.applyQueuePolicy(ctx, plan.Resources.QueueName, queueARN, ruleARNs); err != nil {
+		return fmt.Errorf("applying queue policy to %s, %w", plan.Resources.QueueName, err)
+	}
+	return nil
+}
+
+// sortedKeys returns m's keys in a deterministic order so --dry-run output
+// and logs are stable across runs.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,251 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/samber/lo"
+)
+
+// iamAPI, sqsAPI and eventsAPI are the narrow slices of the AWS SDK this
+// package needs. Defining them as interfaces keeps Plan/Apply unit
+// testable against fakes without pulling in the real SDK clients.
+type iamAPI interface {
+	roleExists(ctx context.Context, name string) (bool, error)
+	ensureRole(ctx context.Context, name, trustPolicy string, policyARNs []string) error
+	instanceProfileExists(ctx context.Context, name string) (bool, error)
+	ensureInstanceProfile(ctx context.Context, name, roleName string) error
+}
+
+type sqsAPI interface {
+	queueExists(ctx context.Context, name string) (bool, error)
+	// ensureQueue returns the queue's ARN, used as the EventBridge target.
+	ensureQueue(ctx context.Context, name string) (string, error)
+	// applyQueuePolicy grants the given rule ARNs (and only those ARNs)
+	// sqs:SendMessage on the queue.
+	applyQueuePolicy(ctx context.Context, name, queueARN string, ruleARNs []string) error
+}
+
+type eventsAPI interface {
+	ruleExists(ctx context.Context, name string) (bool, error)
+	// ensureRule returns the rule's ARN, used to scope the queue policy.
+	ensureRule(ctx context.Context, name, eventPattern, queueARN string) (string, error)
+}
+
+type iamClient struct{ api *iam.IAM }
+
+func newIAMClient(sess *session.Session) *iamClient { return &iamClient{api: iam.New(sess)} }
+
+func (c *iamClient) roleExists(ctx context.Context, name string) (bool, error) {
+	_, err := c.api.GetRoleWithContext(ctx, &iam.GetRoleInput{RoleName: aws.String(name)})
+	if isNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (c *iamClient) ensureRole(ctx context.Context, name, trustPolicy string, policyARNs []string) error {
+	exists, err := c.roleExists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := c.api.CreateRoleWithContext(ctx, &iam.CreateRoleInput{
+			RoleName:                 aws.String(name),
+			AssumeRolePolicyDocument: aws.String(trustPolicy),
+		}); err != nil {
+			return err
+		}
+	}
+	for _, arn := range policyARNs {
+		if _, err := c.api.AttachRolePolicyWithContext(ctx, &iam.AttachRolePolicyInput{
+			RoleName:  aws.String(name),
+			PolicyArn: aws.String(arn),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *iamClient) instanceProfileExists(ctx context.Context, name string) (bool, error) {
+	_, err := c.api.GetInstanceProfileWithContext(ctx, &iam.GetInstanceProfileInput{InstanceProfileName: aws.String(name)})
+	if isNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (c *iamClient) ensureInstanceProfile(ctx context.Context, name, roleName string) error {
+	exists, err := c.instanceProfileExists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := c.api.CreateInstanceProfileWithContext(ctx, &iam.CreateInstanceProfileInput{
+			InstanceProfileName: aws.String(name),
+		}); err != nil {
+			return err
+		}
+	}
+	profile, err := c.api.GetInstanceProfileWithContext(ctx, &iam.GetInstanceProfileInput{InstanceProfileName: aws.String(name)})
+	if err != nil {
+		return err
+	}
+	if len(profile.InstanceProfile.Roles) > 0 {
+		return nil
+	}
+	_, err = c.api.AddRoleToInstanceProfileWithContext(ctx, &iam.AddRoleToInstanceProfileInput{
+		InstanceProfileName: aws.String(name),
+		RoleName:            aws.String(roleName),
+	})
+	return err
+}
+
+type sqsClient struct{ api *sqs.SQS }
+
+func newSQSClient(sess *session.Session) *sqsClient { return &sqsClient{api: sqs.New(sess)} }
+
+func (c *sqsClient) queueExists(ctx context.Context, name string) (bool, error) {
+	_, err := c.api.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{QueueName: aws.String(name)})
+	if isNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (c *sqsClient) ensureQueue(ctx context.Context, name string) (string, error) {
+	out, err := c.api.CreateQueueWithContext(ctx, &sqs.CreateQueueInput{
+		QueueName: aws.String(name),
+		Attributes: map[string]*string{
+			// 5 minutes: long enough for the interruption controller to drain
+			// a batch without the queue aggressively deleting unread messages.
+			sqs.QueueAttributeNameMessageRetentionPeriod: aws.String("300"),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	attrs, err := c.api.GetQueueAttributesWithContext(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       out.QueueUrl,
+		AttributeNames: []*string{aws.String(sqs.QueueAttributeNameQueueArn)},
+	})
+	if err != nil {
+		return "", err
+	}
+	return lo.FromPtr(attrs.Attributes[sqs.QueueAttributeNameQueueArn]), nil
+}
+
+// applyQueuePolicy grants the EventBridge rules in ruleARNs (and only
+// those) sqs:SendMessage on the queue. Without this policy the queue
+// accepts no deliveries at all: EventBridge's PutTargets succeeds at
+// rule-creation time regardless, but every subsequent SendMessage it
+// attempts is silently denied, so interruption notifications never
+// actually arrive. The aws:SourceArn condition keeps the grant scoped to
+// the rules this same Apply call manages, matching the
+// KarpenterInterruptionQueuePolicy statement from the getting-started
+// CloudFormation template rather than opening the queue to any rule in any
+// account.
+func (c *sqsClient) applyQueuePolicy(ctx context.Context, name, queueARN string, ruleARNs []string) error {
+	queueURL, err := c.api.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{QueueName: aws.String(name)})
+	if err != nil {
+		return err
+	}
+	policy, err := json.Marshal(map[string]interface{}{
+		"Version": "2012-10-17",
+		"Id":      "EC2InterruptionPolicy",
+		"Statement": []map[string]interface{}{
+			{
+				"Sid":       "EventsToQueue",
+				"Effect":    "Allow",
+				"Principal": map[string]string{"Service": "events.amazonaws.com"},
+				"Action":    "sqs:SendMessage",
+				"Resource":  queueARN,
+				"Condition": map[string]interface{}{
+					"ArnEquals": map[string]interface{}{"aws:SourceArn": ruleARNs},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling queue policy, %w", err)
+	}
+	_, err = c.api.SetQueueAttributesWithContext(ctx, &sqs.SetQueueAttributesInput{
+		QueueUrl: queueURL.QueueUrl,
+		Attributes: map[string]*string{
+			sqs.QueueAttributeNamePolicy: aws.String(string(policy)),
+		},
+	})
+	return err
+}
+
+type eventsClient struct{ api *cloudwatchevents.CloudWatchEvents }
+
+func newEventsClient(sess *session.Session) *eventsClient {
+	return &eventsClient{api: cloudwatchevents.New(sess)}
+}
+
+func (c *eventsClient) ruleExists(ctx context.Context, name string) (bool, error) {
+	_, err := c.api.DescribeRuleWithContext(ctx, &cloudwatchevents.DescribeRuleInput{Name: aws.String(name)})
+	if isNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (c *eventsClient) ensureRule(ctx context.Context, name, eventPattern, queueARN string) (string, error) {
+	out, err := c.api.PutRuleWithContext(ctx, &cloudwatchevents.PutRuleInput{
+		Name:         aws.String(name),
+		EventPattern: aws.String(eventPattern),
+		State:        aws.String(cloudwatchevents.RuleStateEnabled),
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, err := c.api.PutTargetsWithContext(ctx, &cloudwatchevents.PutTargetsInput{
+		Rule: aws.String(name),
+		Targets: []*cloudwatchevents.Target{{
+			Id:  aws.String("KarpenterInterruptionQueueTarget"),
+			Arn: aws.String(queueARN),
+		}},
+	}); err != nil {
+		return "", err
+	}
+	return lo.FromPtr(out.RuleArn), nil
+}
+
+// isNotFound reports whether err is the "resource doesn't exist" error
+// shape common to the IAM, SQS and EventBridge SDK clients.
+func isNotFound(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+	switch awsErr.Code() {
+	case iam.ErrCodeNoSuchEntityException, sqs.ErrCodeQueueDoesNotExist, cloudwatchevents.ErrCodeResourceNotFoundException:
+		return true
+	default:
+		return false
+	}
+}
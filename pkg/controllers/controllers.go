@@ -0,0 +1,118 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// enabledControllers reports, by name, which controllers this process
+// started. It lets operators in split HA topologies (e.g. a metrics-only
+// replica and a separate provisioning leader) confirm a pod's role at a
+// glance.
+var enabledControllers = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "karpenter",
+		Name:      "enabled_controllers",
+		Help:      "Whether a given controller is enabled in this process, by controller name. 1 if enabled, 0 if disabled.",
+	},
+	[]string{"controller"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(enabledControllers)
+}
+
+// Controller is the common interface implemented by every reconciler
+// Karpenter runs. Name must be stable across releases since it is the
+// identifier operators use in --controllers selectors.
+type Controller interface {
+	reconcile.Reconciler
+	// Name returns the stable, --controllers-selector-facing name of this controller.
+	Name() string
+	// Register wires the controller into the manager (watches, builder, etc).
+	Register(ctx context.Context, m manager.Manager) error
+}
+
+// Manager wraps a controller-runtime Manager with the additional
+// bookkeeping Karpenter's controller binary needs.
+type Manager struct {
+	manager.Manager
+}
+
+// NewManagerOrDie instantiates the controller-runtime manager used to back
+// all of Karpenter's controllers, or panics if the manager cannot be created.
+func NewManagerOrDie(ctx context.Context, config *rest.Config, options manager.Options) Manager {
+	newManager, err := manager.New(config, options)
+	if err != nil {
+		panic(fmt.Sprintf("Unable to start manager, %s", err))
+	}
+	if err := newManager.AddReadyzCheck("manager", healthz.Ping); err != nil {
+		panic(fmt.Sprintf("Unable to add readyz check, %s", err))
+	}
+	if err := newManager.AddHealthzCheck("manager", healthz.Ping); err != nil {
+		panic(fmt.Sprintf("Unable to add healthz check, %s", err))
+	}
+	return Manager{Manager: newManager}
+}
+
+// RegisterControllers registers the supplied, already-filtered controllers
+// with the underlying manager and records which ones are running for the
+// karpenter_enabled_controllers gauge.
+func (m Manager) RegisterControllers(ctx context.Context, controllers ...Controller) Manager {
+	for _, c := range controllers {
+		if err := c.Register(ctx, m.Manager); err != nil {
+			panic(fmt.Sprintf("Unable to register controller %s, %s", c.Name(), err))
+		}
+		enabledControllers.WithLabelValues(c.Name()).Set(1)
+	}
+	return m
+}
+
+// RecordDisabledControllers sets the karpenter_enabled_controllers gauge to
+// 0 for every name, so controllers --controllers left off show up in the
+// metric explicitly instead of operators having to infer disablement from a
+// missing series.
+func RecordDisabledControllers(names ...string) {
+	for _, name := range names {
+		enabledControllers.WithLabelValues(name).Set(0)
+	}
+}
+
+// GetClient returns the client used by controllers registered on this manager.
+func (m Manager) GetClient() client.Client {
+	return m.Manager.GetClient()
+}
+
+// AddMetricsExtraHandler registers an additional handler (e.g. pprof) on the
+// metrics bind address.
+func (m Manager) AddMetricsExtraHandler(path string, handler http.Handler) error {
+	return m.Manager.AddMetricsExtraHandler(path, handler)
+}
+
+// Start starts the manager and blocks until the context is done or an error occurs.
+func (m Manager) Start(ctx context.Context) error {
+	return m.Manager.Start(ctx)
+}
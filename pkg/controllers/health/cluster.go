@@ -0,0 +1,45 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/karpenter/pkg/controllers/state"
+)
+
+// ClusterSyncedCheck reports whether the in-memory cluster state has
+// completed its initial node list sync. Provisioning decisions made before
+// this point see a cluster that looks emptier than it is, so readiness
+// should stay false until the cache has warmed.
+type ClusterSyncedCheck struct {
+	cluster *state.Cluster
+}
+
+// NewClusterSyncedCheck constructs a ClusterSyncedCheck against the shared
+// cluster state.
+func NewClusterSyncedCheck(cluster *state.Cluster) *ClusterSyncedCheck {
+	return &ClusterSyncedCheck{cluster: cluster}
+}
+
+func (c *ClusterSyncedCheck) Name() string { return "cluster-state-synced" }
+
+func (c *ClusterSyncedCheck) Check(ctx context.Context) error {
+	if !c.cluster.Synced(ctx) {
+		return fmt.Errorf("cluster state has not completed its initial node sync")
+	}
+	return nil
+}
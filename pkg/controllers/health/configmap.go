@@ -0,0 +1,49 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// ConfigMapCheck reports whether the configmap informer (config-logging,
+// config-karpenter, ...) has completed its initial sync. The caller marks
+// it synced once cmw.Start returns without error; until then Karpenter is
+// running with default configuration and shouldn't be considered live.
+type ConfigMapCheck struct {
+	synced atomic.Bool
+}
+
+// NewConfigMapCheck constructs an unsynced ConfigMapCheck.
+func NewConfigMapCheck() *ConfigMapCheck {
+	return &ConfigMapCheck{}
+}
+
+// MarkSynced records that the configmap watcher has completed its initial
+// sync. Call this once, after cmw.Start succeeds.
+func (c *ConfigMapCheck) MarkSynced() {
+	c.synced.Store(true)
+}
+
+func (c *ConfigMapCheck) Name() string { return "configmap-watcher" }
+
+func (c *ConfigMapCheck) Check(ctx context.Context) error {
+	if !c.synced.Load() {
+		return fmt.Errorf("configmap watcher has not completed its initial sync")
+	}
+	return nil
+}
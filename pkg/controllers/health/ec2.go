@@ -0,0 +1,57 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/patrickmn/go-cache"
+)
+
+// ec2CacheTTL bounds how often EC2Check actually calls DescribeInstances.
+// A wedged control plane is a sustained condition, not a blip, so probing
+// every readyz poll would just add load without improving signal.
+const ec2CacheTTL = 30 * time.Second
+
+// EC2Check reports whether the EC2 API is reachable with the credentials
+// Karpenter is running with.
+type EC2Check struct {
+	api   ec2iface.EC2API
+	cache *cache.Cache
+}
+
+// NewEC2Check constructs an EC2Check against the given EC2 client.
+func NewEC2Check(api ec2iface.EC2API) *EC2Check {
+	return &EC2Check{api: api, cache: cache.New(ec2CacheTTL, ec2CacheTTL)}
+}
+
+func (c *EC2Check) Name() string { return "ec2-reachability" }
+
+func (c *EC2Check) Check(ctx context.Context) error {
+	if _, ok := c.cache.Get("ok"); ok {
+		return nil
+	}
+	// MaxResults asks for the smallest possible page: this check only cares
+	// whether the call succeeds, not its contents.
+	if _, err := c.api.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{MaxResults: aws.Int64(5)}); err != nil {
+		return err
+	}
+	c.cache.SetDefault("ok", struct{}{})
+	return nil
+}
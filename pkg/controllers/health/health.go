@@ -0,0 +1,44 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health defines domain-specific liveness/readiness checks for
+// Karpenter, beyond the "is the process alive" ping that
+// controllers.NewManagerOrDie registers. A check wired in here as a
+// readiness check flips the pod unready when, for example, the AWS control
+// plane is unreachable or IRSA credentials have been revoked, which stops
+// kube-proxy from routing leader-election traffic to a wedged pod.
+package health
+
+import (
+	"context"
+	"net/http"
+)
+
+// Checker is a single named liveness or readiness probe.
+type Checker interface {
+	// Name identifies this check in logs and in the healthz/readyz endpoint
+	// it's registered under (e.g. /readyz/ec2-reachability).
+	Name() string
+	// Check returns nil if the subsystem is healthy, or an error describing
+	// why it isn't.
+	Check(ctx context.Context) error
+}
+
+// AsHealthzCheck adapts a Checker to the func(*http.Request) error signature
+// controller-runtime's manager.AddHealthzCheck/AddReadyzCheck expect.
+func AsHealthzCheck(c Checker) func(*http.Request) error {
+	return func(req *http.Request) error {
+		return c.Check(req.Context())
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+)
+
+// SQSCheck reports whether Karpenter can still read the interruption
+// queue's attributes, a reasonable proxy for the sqs:ReceiveMessage /
+// sqs:GetQueueAttributes permissions the interruption controller needs.
+// It is a no-op when no interruption queue is configured.
+type SQSCheck struct {
+	api       sqsiface.SQSAPI
+	queueName string
+}
+
+// NewSQSCheck constructs a SQSCheck for the given queue name. An empty
+// queueName means no interruption queue is configured; Check always
+// succeeds in that case.
+func NewSQSCheck(api sqsiface.SQSAPI, queueName string) *SQSCheck {
+	return &SQSCheck{api: api, queueName: queueName}
+}
+
+func (c *SQSCheck) Name() string { return "sqs-interruption-queue" }
+
+func (c *SQSCheck) Check(ctx context.Context) error {
+	if c.queueName == "" {
+		return nil
+	}
+	queueURL, err := c.api.GetQueueUrlWithContext(ctx, &sqs.GetQueueUrlInput{QueueName: aws.String(c.queueName)})
+	if err != nil {
+		return err
+	}
+	_, err = c.api.GetQueueAttributesWithContext(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       queueURL.QueueUrl,
+		AttributeNames: aws.StringSlice([]string{sqs.QueueAttributeNameQueueArn}),
+	})
+	return err
+}
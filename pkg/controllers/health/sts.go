@@ -0,0 +1,57 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/patrickmn/go-cache"
+)
+
+// credentialsCacheTTL is shorter than EC2Check's: a revoked IRSA token is
+// exactly the failure mode this check exists to catch quickly, so it's
+// worth re-verifying more often.
+const credentialsCacheTTL = 10 * time.Second
+
+// CredentialsCheck reports whether Karpenter's AWS credentials (IMDS
+// instance profile or IRSA web identity token) are still valid. A revoked
+// IRSA token surfaces here as a failure well before it would otherwise be
+// noticed as a wave of AccessDenied errors from the cloud provider.
+type CredentialsCheck struct {
+	api   stsiface.STSAPI
+	cache *cache.Cache
+}
+
+// NewCredentialsCheck constructs a CredentialsCheck against the given STS client.
+func NewCredentialsCheck(api stsiface.STSAPI) *CredentialsCheck {
+	return &CredentialsCheck{api: api, cache: cache.New(credentialsCacheTTL, credentialsCacheTTL)}
+}
+
+func (c *CredentialsCheck) Name() string { return "aws-credentials" }
+
+func (c *CredentialsCheck) Check(ctx context.Context) error {
+	if _, ok := c.cache.Get("ok"); ok {
+		return nil
+	}
+	if _, err := c.api.GetCallerIdentityWithContext(ctx, &sts.GetCallerIdentityInput{}); err != nil {
+		return fmt.Errorf("credentials are invalid or expired, %w", err)
+	}
+	c.cache.SetDefault("ok", struct{}{})
+	return nil
+}
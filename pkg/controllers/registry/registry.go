@@ -0,0 +1,98 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registry resolves the --controllers selector against the set of
+// controllers Karpenter knows how to run, following the same "*", "-name"
+// convention used by kube-controller-manager's --controllers flag.
+package registry
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/karpenter/pkg/controllers"
+)
+
+// Registration describes a single controller that can be selectively
+// enabled or disabled via --controllers.
+type Registration struct {
+	// Name is the stable, user-facing identifier used in --controllers
+	// selectors (e.g. "provisioning", "metricspod").
+	Name string
+	// Description is a short, human readable summary of what the controller
+	// does. Neither --help nor the startup logs print it.
+	Description string
+	// DefaultEnabled determines whether this controller runs when the
+	// selector includes "*" without an explicit "-name" exclusion.
+	DefaultEnabled bool
+	// Controller is the controller instance to register when enabled.
+	Controller controllers.Controller
+}
+
+// Resolve applies the --controllers selector (e.g. "*,-metricspod,-counter")
+// against the supplied registrations, returning the subset that should be
+// started, in registration order. An unknown controller name in the
+// selector is treated as a configuration error.
+func Resolve(selectors []string, registrations []Registration) ([]Registration, error) {
+	// Every controller starts out disabled. "*" is what opts registrations
+	// into their DefaultEnabled bit, but only for controllers not named
+	// explicitly: "name" and "-name" win over "*" wherever they appear in the
+	// list. A selector list that never includes "*" is an allow-list where
+	// only explicitly named controllers run, matching kube-controller-manager
+	// semantics.
+	byName := make(map[string]bool, len(registrations))
+	for _, r := range registrations {
+		byName[r.Name] = false
+	}
+	explicit := make(map[string]bool, len(selectors))
+	for _, selector := range selectors {
+		switch {
+		case selector == "*":
+			for name, reg := range indexByName(registrations) {
+				if !explicit[name] {
+					byName[name] = reg.DefaultEnabled
+				}
+			}
+		case strings.HasPrefix(selector, "-"):
+			name := strings.TrimPrefix(selector, "-")
+			if _, ok := byName[name]; !ok {
+				return nil, fmt.Errorf("unknown controller %q in --controllers", name)
+			}
+			byName[name] = false
+			explicit[name] = true
+		default:
+			if _, ok := byName[selector]; !ok {
+				return nil, fmt.Errorf("unknown controller %q in --controllers", selector)
+			}
+			byName[selector] = true
+			explicit[selector] = true
+		}
+	}
+
+	enabled := make([]Registration, 0, len(registrations))
+	for _, r := range registrations {
+		if byName[r.Name] {
+			enabled = append(enabled, r)
+		}
+	}
+	return enabled, nil
+}
+
+func indexByName(registrations []Registration) map[string]Registration {
+	out := make(map[string]Registration, len(registrations))
+	for _, r := range registrations {
+		out[r.Name] = r
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	registrations := []Registration{
+		{Name: "provisioning", DefaultEnabled: true},
+		{Name: "metricspod", DefaultEnabled: true},
+		{Name: "counter", DefaultEnabled: true},
+		{Name: "experimental", DefaultEnabled: false},
+	}
+	for _, tc := range []struct {
+		name      string
+		selectors []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "star enables every default-enabled controller",
+			selectors: []string{"*"},
+			want:      []string{"provisioning", "metricspod", "counter"},
+		},
+		{
+			name:      "star then explicit exclusion",
+			selectors: []string{"*", "-metricspod", "-counter"},
+			want:      []string{"provisioning"},
+		},
+		{
+			name:      "exclusion before star still disables it",
+			selectors: []string{"-metricspod", "*"},
+			want:      []string{"provisioning", "counter"},
+		},
+		{
+			name:      "explicit name without star enables only that controller",
+			selectors: []string{"experimental"},
+			want:      []string{"experimental"},
+		},
+		{
+			name:      "no selectors enables nothing",
+			selectors: nil,
+			want:      nil,
+		},
+		{
+			name:      "unknown controller name is an error",
+			selectors: []string{"*", "-does-not-exist"},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown explicit controller name is an error",
+			selectors: []string{"does-not-exist"},
+			wantErr:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Resolve(tc.selectors, registrations)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("Resolve(%v) = nil error, want an error", tc.selectors)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve(%v) unexpected error: %s", tc.selectors, err)
+			}
+			gotNames := make([]string, 0, len(got))
+			for _, r := range got {
+				gotNames = append(gotNames, r.Name)
+			}
+			sort.Strings(gotNames)
+			want := append([]string(nil), tc.want...)
+			sort.Strings(want)
+			if len(gotNames) != len(want) {
+				t.Fatalf("Resolve(%v) = %v, want %v", tc.selectors, gotNames, want)
+			}
+			for i := range want {
+				if gotNames[i] != want[i] {
+					t.Fatalf("Resolve(%v) = %v, want %v", tc.selectors, gotNames, want)
+				}
+			}
+		})
+	}
+}
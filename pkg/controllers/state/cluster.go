@@ -0,0 +1,61 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package state tracks whether Karpenter's node cache has completed its
+// initial sync. It is deliberately narrow: it backs the "cluster-state
+// cache warmed" readiness signal and nothing else. The scheduling state
+// (capacity/bin-packing, daemonset overhead, volume limits, and so on) that
+// provisioning, termination and counter read from is a separate, much
+// larger cache this package does not attempt to model or replace.
+package state
+
+import (
+	"context"
+	"sync/atomic"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/karpenter/pkg/cloudprovider"
+)
+
+// Cluster holds the subset of cluster-sync bookkeeping the readiness check
+// needs. kubeClient and cloudProvider are threaded through because the
+// controllers registered alongside Cluster (provisioning, counter, ...)
+// share it as their handle onto cluster state; this package
+// only ever reads synced off of it.
+type Cluster struct {
+	kubeClient    client.Client
+	cloudProvider cloudprovider.CloudProvider
+
+	synced atomic.Bool
+}
+
+// NewCluster constructs an unsynced Cluster.
+func NewCluster(kubeClient client.Client, cloudProvider cloudprovider.CloudProvider) *Cluster {
+	return &Cluster{kubeClient: kubeClient, cloudProvider: cloudProvider}
+}
+
+// Synced reports whether the state-node controller has completed its
+// initial node list. Provisioning decisions made before this point see a
+// cluster that looks emptier than it is, so callers should treat an
+// unsynced cluster as not yet safe to schedule against.
+func (c *Cluster) Synced(ctx context.Context) bool {
+	return c.synced.Load()
+}
+
+// markSynced records that the initial node list has completed. Called once
+// by NodeController after the manager's informer cache reports synced.
+func (c *Cluster) markSynced() {
+	c.synced.Store(true)
+}
@@ -0,0 +1,60 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NodeController watches nodes so the manager's informer cache completes
+// its initial list, which is what flips Cluster.Synced. It otherwise
+// no-ops on each reconcile; node bookkeeping beyond that belongs to the
+// scheduling-state cache this package doesn't model.
+type NodeController struct {
+	kubeClient client.Client
+	cluster    *Cluster
+}
+
+// NewNodeController constructs a NodeController that watches nodes on
+// cluster's behalf.
+func NewNodeController(kubeClient client.Client, cluster *Cluster) *NodeController {
+	return &NodeController{kubeClient: kubeClient, cluster: cluster}
+}
+
+func (c *NodeController) Name() string { return "state-node" }
+
+// Register wires the controller into the manager and, once the manager's
+// informer cache reports an initial sync, flips Cluster.Synced.
+func (c *NodeController) Register(ctx context.Context, m manager.Manager) error {
+	if err := controllerruntime.NewControllerManagedBy(m).For(&v1.Node{}).Complete(c); err != nil {
+		return err
+	}
+	go func() {
+		if m.GetCache().WaitForCacheSync(ctx) {
+			c.cluster.markSynced()
+		}
+	}()
+	return nil
+}
+
+func (c *NodeController) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
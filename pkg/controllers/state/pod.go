@@ -0,0 +1,50 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package state
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	controllerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// PodController watches pods on cluster's behalf. Pod changes don't affect
+// Cluster.Synced (only the initial node list does); like NodeController,
+// pod bookkeeping for scheduling purposes belongs to the separate
+// scheduling-state cache this package doesn't model.
+type PodController struct {
+	kubeClient client.Client
+	cluster    *Cluster
+}
+
+// NewPodController constructs a PodController that watches pods on
+// cluster's behalf.
+func NewPodController(kubeClient client.Client, cluster *Cluster) *PodController {
+	return &PodController{kubeClient: kubeClient, cluster: cluster}
+}
+
+func (c *PodController) Name() string { return "state-pod" }
+
+func (c *PodController) Register(ctx context.Context, m manager.Manager) error {
+	return controllerruntime.NewControllerManagedBy(m).For(&v1.Pod{}).Complete(c)
+}
+
+func (c *PodController) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return reconcile.Result{}, nil
+}
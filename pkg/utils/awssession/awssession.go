@@ -0,0 +1,33 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package awssession constructs the single, shared AWS session Karpenter
+// uses everywhere it talks to the AWS API, so the controller's cloud
+// provider and the `karpenter init` bootstrap command pick up credentials,
+// region discovery and retry behavior identically.
+package awssession
+
+import (
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// New returns an AWS session built from the standard credential chain
+// (environment, shared config, EC2/ECS metadata, IRSA), with region
+// resolved the same way: explicit config, then AWS_REGION, then the
+// EC2 instance metadata service.
+func New() (*session.Session, error) {
+	return session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	})
+}
@@ -0,0 +1,132 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package options contains the flag driven configuration surface for the
+// controller binary. It mirrors the flag <-> struct-field conventions used
+// elsewhere in the k8s controller-manager family so operators can configure
+// Karpenter the same way they configure kube-controller-manager or the
+// cloud-controller-manager. Environment variables are not read.
+package options
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Options exposes the CLI flags used to configure the controller binary.
+type Options struct {
+	*flag.FlagSet
+	MetricsPort     int
+	HealthProbePort int
+	KubeClientQPS   int
+	KubeClientBurst int
+	EnableProfiling bool
+	MemoryLimit     int64
+
+	// Controllers holds the --controllers selectors, split on commas (e.g.
+	// "*,-metricspod,-counter" becomes ["*", "-metricspod", "-counter"]).
+	// Use pkg/controllers/registry to resolve them
+	// against the set of registered controllers.
+	Controllers []string
+
+	// Leader election. Mirrors the flags exposed by kube-controller-manager
+	// and friends so operators can tune them the same way everywhere.
+	LeaderElect                  bool
+	LeaderElectLeaseDuration     time.Duration
+	LeaderElectRenewDeadline     time.Duration
+	LeaderElectRetryPeriod       time.Duration
+	LeaderElectResourceLock      string
+	LeaderElectResourceName      string
+	LeaderElectResourceNamespace string
+
+	// AWSInterruptionQueueName is the name of the SQS queue the interruption
+	// controller drains Spot interruption, rebalance, and instance
+	// state-change notifications from. Empty means interruption handling is
+	// disabled.
+	AWSInterruptionQueueName string
+}
+
+// New constructs an Options with an initialized FlagSet. The CLI binds this
+// FlagSet to a cobra command (cmd.Flags().AddGoFlagSet(opts.FlagSet)) so
+// flags are populated when cobra parses the process's arguments.
+func New() *Options {
+	opts := &Options{}
+	f := flag.NewFlagSet("karpenter", flag.ContinueOnError)
+	opts.FlagSet = f
+
+	f.IntVar(&opts.MetricsPort, "metrics-port", 8080, "The port the metric endpoint binds to for operating metrics about the controller itself")
+	f.IntVar(&opts.HealthProbePort, "health-probe-port", 8081, "The port the health probe endpoint binds to for reporting controller health")
+	f.IntVar(&opts.KubeClientQPS, "kube-client-qps", 200, "The smoothed rate of qps to kube-apiserver")
+	f.IntVar(&opts.KubeClientBurst, "kube-client-burst", 300, "The maximum allowed burst of queries to the kube-apiserver")
+	f.BoolVar(&opts.EnableProfiling, "enable-profiling", false, "Enable the pprof profiling server")
+	f.Int64Var(&opts.MemoryLimit, "memory-limit", -1, "Memory limit on the container running the controller. The grace period for pod termination will be shortened based on the limit defined")
+	f.Var(newControllersValue(&opts.Controllers), "controllers", "A comma separated list of controllers to enable, '*' enables all on-by-default controllers, '-foo' disables controller 'foo'")
+
+	f.BoolVar(&opts.LeaderElect, "leader-elect", true, "Start a leader election client and gain leadership before executing the main loop. Disable this when running a single, non-HA instance of Karpenter")
+	f.DurationVar(&opts.LeaderElectLeaseDuration, "leader-elect-lease-duration", 15*time.Second, "The duration that non-leader candidates will wait after observing a leadership renewal until attempting to acquire leadership of the led but unrenewed lease")
+	f.DurationVar(&opts.LeaderElectRenewDeadline, "leader-elect-renew-deadline", 10*time.Second, "The interval between attempts by the acting leader to renew its leadership before it stops leading. This must be less than the lease duration")
+	f.DurationVar(&opts.LeaderElectRetryPeriod, "leader-elect-retry-period", 2*time.Second, "The duration clients should wait between attempting acquisition and renewal of leadership")
+	f.StringVar(&opts.LeaderElectResourceLock, "leader-elect-resource-lock", "leases", "The resource lock to use for leader election. Supported options are 'leases', 'endpointsleases' and 'configmapsleases'")
+	f.StringVar(&opts.LeaderElectResourceName, "leader-elect-resource-name", "karpenter-leader-election", "The name of the resource object used for the leader election lock")
+	f.StringVar(&opts.LeaderElectResourceNamespace, "leader-elect-resource-namespace", "", "The namespace of the resource object used for the leader election lock. Defaults to the namespace Karpenter is running in")
+
+	f.StringVar(&opts.AWSInterruptionQueueName, "aws-interruption-queue-name", "", "The name of the SQS queue to drain Spot interruption, rebalance and instance state-change notifications from. Disabled if not set")
+
+	return opts
+}
+
+// Validate checks invariants across flags that can't be expressed by the
+// flag package alone. The CLI (cmd/controller) calls this once cobra has
+// parsed the process's arguments into these flags.
+func (o *Options) Validate() error {
+	if o.LeaderElect && o.LeaderElectRenewDeadline >= o.LeaderElectLeaseDuration {
+		return fmt.Errorf("leader-elect-renew-deadline (%s) must be less than leader-elect-lease-duration (%s)", o.LeaderElectRenewDeadline, o.LeaderElectLeaseDuration)
+	}
+	return nil
+}
+
+// controllersValue implements flag.Value so that --controllers can be passed
+// as a comma-separated flag (e.g. --controllers=*,-counter). When the flag is
+// given more than once, the last occurrence replaces the earlier ones.
+type controllersValue struct {
+	controllers *[]string
+}
+
+func newControllersValue(controllers *[]string) *controllersValue {
+	*controllers = []string{"*"}
+	return &controllersValue{controllers: controllers}
+}
+
+func (c *controllersValue) String() string {
+	if c.controllers == nil {
+		return ""
+	}
+	return strings.Join(*c.controllers, ",")
+}
+
+func (c *controllersValue) Set(value string) error {
+	var controllers []string
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			controllers = append(controllers, s)
+		}
+	}
+	if len(controllers) == 0 {
+		return fmt.Errorf("--controllers may not be empty")
+	}
+	*c.controllers = controllers
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		leaderElect  bool
+		leaseDur     time.Duration
+		renewDeadDur time.Duration
+		wantErr      bool
+	}{
+		{
+			name:         "renew deadline less than lease duration passes",
+			leaderElect:  true,
+			leaseDur:     15 * time.Second,
+			renewDeadDur: 10 * time.Second,
+		},
+		{
+			name:         "renew deadline equal to lease duration fails",
+			leaderElect:  true,
+			leaseDur:     15 * time.Second,
+			renewDeadDur: 15 * time.Second,
+			wantErr:      true,
+		},
+		{
+			name:         "renew deadline greater than lease duration fails",
+			leaderElect:  true,
+			leaseDur:     15 * time.Second,
+			renewDeadDur: 20 * time.Second,
+			wantErr:      true,
+		},
+		{
+			name:         "leader election disabled bypasses the check",
+			leaderElect:  false,
+			leaseDur:     15 * time.Second,
+			renewDeadDur: 20 * time.Second,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &Options{
+				LeaderElect:              tc.leaderElect,
+				LeaderElectLeaseDuration: tc.leaseDur,
+				LeaderElectRenewDeadline: tc.renewDeadDur,
+			}
+			err := o.Validate()
+			if tc.wantErr && err == nil {
+				t.Fatalf("Validate() = nil error, want an error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("Validate() unexpected error: %s", err)
+			}
+		})
+	}
+}